@@ -3,79 +3,268 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/account"
-	"github.com/aws/aws-sdk-go-v2/service/account/types"
-	"github.com/aws/aws-sdk-go-v2/service/rds"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	appconfig "github.com/deeb00/rdscli_exporter/pkg/config"
+	"github.com/deeb00/rdscli_exporter/pkg/discovery"
+	"github.com/deeb00/rdscli_exporter/pkg/metrics"
+	"github.com/deeb00/rdscli_exporter/pkg/remotewrite"
+	"github.com/deeb00/rdscli_exporter/pkg/store"
+	"github.com/deeb00/rdscli_exporter/pkg/utils"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	log "github.com/sirupsen/logrus"
+	"github.com/redis/go-redis/v9"
+	"log/slog"
 	"net/http"
+	"os"
 	"os/signal"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
 
 var (
 	baseLabels = []string{"dimension_DBInstanceIdentifier", "az", "secondary_az", "storage_type", "region", "db_instance_class", "engine"}
-	tags       = []string{"purpose", "team", "region", "environment"}
-	dynLabels  = createDynLabels(baseLabels, tags)
 
-	allocatedStorageDesc = prometheus.NewDesc(
-		"aws_rds_allocated_storage",
-		"Allocated storage for RDS instance in GB",
-		dynLabels, nil,
+	scrapeDurationDesc = prometheus.NewDesc(
+		"aws_rds_exporter_scrape_duration_seconds",
+		"Duration of the last cache refresh",
+		nil, nil,
 	)
-	maxAllocatedStorageDesc = prometheus.NewDesc(
-		"aws_rds_max_allocated_storage",
-		"Max allocated storage for RDS instance in GB",
-		dynLabels, nil,
+	lastSuccessTimestampDesc = prometheus.NewDesc(
+		"aws_rds_exporter_last_success_timestamp",
+		"Unix timestamp of the last successful cache refresh",
+		nil, nil,
 	)
-	iopsDesc = prometheus.NewDesc(
-		"aws_rds_iops",
-		"IOPS for RDS instance",
-		dynLabels, nil,
-	)
-	storageThroughputDesc = prometheus.NewDesc(
-		"aws_rds_storage_throughput",
-		"Storage throughput for RDS instance",
-		dynLabels, nil,
+	scrapeErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aws_rds_exporter_scrape_errors_total",
+		Help: "Total number of errors encountered while refreshing the cache, per region",
+	}, []string{"region"})
+	configReloadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aws_rds_exporter_config_reloads_total",
+		Help: "Total number of config file reload attempts, by result",
+	}, []string{"result"})
+	cacheStaleSecondsDesc = prometheus.NewDesc(
+		"aws_rds_exporter_cache_stale_seconds",
+		"Seconds since the last successful DescribeDBInstances call for a region",
+		[]string{"region"}, nil,
 	)
 )
 
-type RDSExporter struct {
-	sdkConfig  aws.Config
-	cache      []prometheus.Metric
-	cacheTTL   time.Duration
-	lastUpdate time.Time
-	mu         sync.RWMutex
-	updateMu   sync.Mutex
+func init() {
+	prometheus.MustRegister(scrapeErrorsTotal, configReloadsTotal)
+}
+
+// metricDescs holds the label-dependent metric descriptors. They are rebuilt
+// whenever the config's tag list changes, since a prometheus.Desc's labels
+// are fixed at creation time.
+type metricDescs struct {
+	allocatedStorage    *prometheus.Desc
+	maxAllocatedStorage *prometheus.Desc
+	iops                *prometheus.Desc
+	storageThroughput   *prometheus.Desc
 }
 
-func createDynLabels(baseLabels []string, tags []string) []string {
-	for _, tag := range tags {
-		baseLabels = append(baseLabels, "tag_"+tag)
+func buildMetricDescs(tags []string) *metricDescs {
+	dynLabels := append(slices.Clone(baseLabels), tagLabels(tags)...)
+	return &metricDescs{
+		allocatedStorage: prometheus.NewDesc(
+			"aws_rds_allocated_storage",
+			"Allocated storage for RDS instance in GB",
+			dynLabels, nil,
+		),
+		maxAllocatedStorage: prometheus.NewDesc(
+			"aws_rds_max_allocated_storage",
+			"Max allocated storage for RDS instance in GB",
+			dynLabels, nil,
+		),
+		iops: prometheus.NewDesc(
+			"aws_rds_iops",
+			"IOPS for RDS instance",
+			dynLabels, nil,
+		),
+		storageThroughput: prometheus.NewDesc(
+			"aws_rds_storage_throughput",
+			"Storage throughput for RDS instance",
+			dynLabels, nil,
+		),
 	}
-	return baseLabels
 }
 
-func NewRDSExporter(sdkConfig aws.Config, ttl *time.Duration) *RDSExporter {
-	return &RDSExporter{
+func tagLabels(tags []string) []string {
+	labels := make([]string, len(tags))
+	for i, tag := range tags {
+		labels[i] = "tag_" + tag
+	}
+	return labels
+}
+
+func (d *metricDescs) descForName(name string) (*prometheus.Desc, bool) {
+	switch name {
+	case "aws_rds_allocated_storage":
+		return d.allocatedStorage, true
+	case "aws_rds_max_allocated_storage":
+		return d.maxAllocatedStorage, true
+	case "aws_rds_iops":
+		return d.iops, true
+	case "aws_rds_storage_throughput":
+		return d.storageThroughput, true
+	default:
+		return nil, false
+	}
+}
+
+// sampleToMetric rebuilds a prometheus.Metric from a persisted Sample,
+// ordering its label values to match the desc built from tags.
+func sampleToMetric(descs *metricDescs, tags []string, sample metrics.Sample) (prometheus.Metric, error) {
+	desc, ok := descs.descForName(sample.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown metric name %q in secondary store", sample.Name)
+	}
+	labelNames := append(slices.Clone(baseLabels), tagLabels(tags)...)
+	labelValues := make([]string, len(labelNames))
+	for i, name := range labelNames {
+		labelValues[i] = sample.Labels[name]
+	}
+	return prometheus.NewConstMetric(desc, prometheus.GaugeValue, sample.Value, labelValues...)
+}
+
+// collectedMetric pairs a prometheus.Metric (served on /metrics) with the
+// equivalent metrics.Sample (pushed by the remote-write writer and, once
+// persisted, read back by a MetricStore). Both are built from the same
+// desc/name/labels/value at the point of collection so they never drift.
+type collectedMetric struct {
+	metric prometheus.Metric
+	sample metrics.Sample
+}
+
+func newCollectedMetric(desc *prometheus.Desc, sampleName string, value float64, labelNames, labelValues []string) collectedMetric {
+	labels := make(map[string]string, len(labelNames))
+	for i, name := range labelNames {
+		labels[name] = labelValues[i]
+	}
+	return collectedMetric{
+		metric: prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...),
+		sample: metrics.Sample{Name: sampleName, Labels: labels, Value: value},
+	}
+}
+
+// configSnapshot pairs a Config with the metricDescs built from its tag
+// list. The two must always be swapped together: metricDescs' labels are
+// fixed at creation time from cfg.Tags, so a reader that loaded the new cfg
+// but the old descs (or vice versa) would build a label slice sized for one
+// tag count against a Desc sized for another, and
+// prometheus.MustNewConstMetric panics on the cardinality mismatch.
+type configSnapshot struct {
+	cfg   *appconfig.Config
+	descs *metricDescs
+}
+
+func newConfigSnapshot(cfg *appconfig.Config) *configSnapshot {
+	return &configSnapshot{cfg: cfg, descs: buildMetricDescs(cfg.Tags)}
+}
+
+// RDSExporter is a prometheus.Collector backed by a cache that is refreshed
+// in the background by a discovery loop started in main. Collect never
+// performs I/O; it only ever emits whatever is currently in the cache.
+type RDSExporter struct {
+	sdkConfig   aws.Config
+	discoverer  discovery.Discoverer
+	cache       []prometheus.Metric
+	mu          sync.RWMutex
+	ready       atomic.Bool
+	remoteWrite *remotewrite.Writer
+	store       store.MetricStore
+
+	snapshot atomic.Pointer[configSnapshot]
+
+	lastDuration atomic.Value // time.Duration
+	lastSuccess  atomic.Value // time.Time
+}
+
+func NewRDSExporter(sdkConfig aws.Config, cfg *appconfig.Config, metricStore store.MetricStore, discoverer discovery.Discoverer) *RDSExporter {
+	e := &RDSExporter{
 		sdkConfig:  sdkConfig,
+		discoverer: discoverer,
 		cache:      []prometheus.Metric{},
-		cacheTTL:   *ttl,
-		lastUpdate: time.Time{},
+		store:      metricStore,
+	}
+	e.lastDuration.Store(time.Duration(0))
+	e.lastSuccess.Store(time.Time{})
+	e.snapshot.Store(newConfigSnapshot(cfg))
+	if len(cfg.RemoteWrite.Endpoints) > 0 {
+		e.remoteWrite = remotewrite.NewWriter(cfg.RemoteWrite)
+	}
+	return e
+}
+
+// Warm loads the secondary store's last snapshot into the in-memory cache,
+// so /metrics and /readyz are useful immediately instead of waiting for the
+// first DescribeDBInstances round trip.
+func (e *RDSExporter) Warm(ctx context.Context, logger *slog.Logger) {
+	samples, ts, err := e.store.Get(ctx)
+	if err != nil {
+		logger.Error("Error warming cache from secondary store", "error", err)
+		return
+	}
+	if len(samples) == 0 {
+		return
+	}
+
+	snap := e.snapshot.Load()
+	cache := make([]prometheus.Metric, 0, len(samples))
+	for _, sample := range samples {
+		metric, err := sampleToMetric(snap.descs, snap.cfg.Tags, sample)
+		if err != nil {
+			logger.Error("Error rebuilding metric from secondary store", "error", err)
+			continue
+		}
+		cache = append(cache, metric)
+	}
+
+	e.mu.Lock()
+	e.cache = cache
+	e.mu.Unlock()
+
+	e.lastSuccess.Store(ts)
+	e.ready.Store(true)
+	logger.Info("Warmed cache from secondary store", "samples", len(cache), "age", time.Since(ts).String())
+}
+
+// Ready reports whether at least one cache refresh has completed or the
+// cache has been warmed with samples from the secondary store.
+func (e *RDSExporter) Ready() bool {
+	return e.ready.Load()
+}
+
+// Reload reads the config file at path and, on success, atomically swaps it
+// in along with the label descriptors it implies. The previous config keeps
+// serving if the file fails to parse.
+func (e *RDSExporter) Reload(path string, logger *slog.Logger) error {
+	cfg, err := appconfig.Load(path)
+	if err != nil {
+		logger.Error("Error reloading config", "error", err)
+		configReloadsTotal.WithLabelValues("failure").Inc()
+		return err
 	}
+	e.snapshot.Store(newConfigSnapshot(cfg))
+	configReloadsTotal.WithLabelValues("success").Inc()
+	logger.Info("Config reloaded")
+	return nil
 }
 
 func (e *RDSExporter) Describe(ch chan<- *prometheus.Desc) {
-	ch <- allocatedStorageDesc
-	ch <- maxAllocatedStorageDesc
-	ch <- iopsDesc
-	ch <- storageThroughputDesc
+	descs := e.snapshot.Load().descs
+	ch <- descs.allocatedStorage
+	ch <- descs.maxAllocatedStorage
+	ch <- descs.iops
+	ch <- descs.storageThroughput
+	ch <- scrapeDurationDesc
+	ch <- lastSuccessTimestampDesc
+	ch <- cacheStaleSecondsDesc
 }
 
 func (e *RDSExporter) Collect(ch chan<- prometheus.Metric) {
@@ -83,144 +272,369 @@ func (e *RDSExporter) Collect(ch chan<- prometheus.Metric) {
 	for _, metric := range e.cache {
 		ch <- metric
 	}
-	cacheExpired := time.Since(e.lastUpdate) >= e.cacheTTL
 	e.mu.RUnlock()
 
-	if cacheExpired {
-		go e.updateCache()
+	ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, e.lastDuration.Load().(time.Duration).Seconds())
+	if lastSuccess := e.lastSuccess.Load().(time.Time); !lastSuccess.IsZero() {
+		ch <- prometheus.MustNewConstMetric(lastSuccessTimestampDesc, prometheus.GaugeValue, float64(lastSuccess.Unix()))
 	}
-}
 
-func (e *RDSExporter) updateCache() {
-	e.updateMu.Lock()
-	defer e.updateMu.Unlock()
+	if reporter, ok := e.discoverer.(discovery.StaleReporter); ok {
+		for region, seconds := range reporter.StaleSeconds() {
+			ch <- prometheus.MustNewConstMetric(cacheStaleSecondsDesc, prometheus.GaugeValue, seconds, region)
+		}
+	}
+}
 
-	accountClient := account.NewFromConfig(e.sdkConfig)
-	regionOutput, err := accountClient.ListRegions(context.TODO(), &account.ListRegionsInput{
-		RegionOptStatusContains: []types.RegionOptStatus{types.RegionOptStatusEnabled, types.RegionOptStatusEnabledByDefault}})
-	if err != nil {
-		log.Printf("Error listing regions: %v", err)
+// runCacheLoop drives e.discoverer until ctx is cancelled. Snapshot
+// discoverers are re-invoked on every tick, modelled after Prometheus's
+// scrape manager; a continuous discoverer is instead started once and left
+// to stream incremental updates for the lifetime of ctx.
+func (e *RDSExporter) runCacheLoop(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	if e.discoverer.Continuous() {
+		e.runContinuousCache(ctx, logger)
 		return
 	}
 
-	var wg sync.WaitGroup
-	metricsChan := make(chan prometheus.Metric, 100)
+	e.updateCache(ctx, logger)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.updateCache(ctx, logger)
+		}
+	}
+}
+
+// metricsForTarget builds the enabled prometheus metrics for a single
+// discovered RDS instance.
+func metricsForTarget(descs *metricDescs, labelNames []string, cfg *appconfig.Config, target discovery.RDSTarget) []collectedMetric {
+	labels := make([]string, 0, len(labelNames))
+	labels = append(labels, target.Identifier, target.AvailabilityZone, target.SecondaryAvailabilityZone, target.StorageType, target.Region, target.Class, target.Engine)
+	for _, tag := range cfg.Tags {
+		labels = append(labels, target.Tags[tag])
+	}
+
+	var collected []collectedMetric
+	if cfg.Enabled("allocated_storage") && target.AllocatedStorage != nil {
+		collected = append(collected, newCollectedMetric(descs.allocatedStorage, "aws_rds_allocated_storage", float64(*target.AllocatedStorage), labelNames, labels))
+	}
+	if cfg.Enabled("max_allocated_storage") && target.MaxAllocatedStorage != nil {
+		collected = append(collected, newCollectedMetric(descs.maxAllocatedStorage, "aws_rds_max_allocated_storage", float64(*target.MaxAllocatedStorage), labelNames, labels))
+	}
+	if cfg.Enabled("iops") && target.IOPS != nil {
+		collected = append(collected, newCollectedMetric(descs.iops, "aws_rds_iops", float64(*target.IOPS), labelNames, labels))
+	}
+	if cfg.Enabled("storage_throughput") && target.StorageThroughput != nil {
+		collected = append(collected, newCollectedMetric(descs.storageThroughput, "aws_rds_storage_throughput", float64(*target.StorageThroughput), labelNames, labels))
+	}
+	return collected
+}
 
-	for _, region := range regionOutput.Regions {
-		wg.Add(1)
-		go func(regionName string) {
-			defer wg.Done()
-			e.collectRegionMetrics(regionName, metricsChan)
-		}(*region.RegionName)
+// buildCacheFromTargets turns a full set of discovered targets into the
+// parallel prometheus.Metric/metrics.Sample slices the rest of the exporter
+// consumes.
+func buildCacheFromTargets(descs *metricDescs, cfg *appconfig.Config, targets []discovery.RDSTarget) ([]prometheus.Metric, []metrics.Sample) {
+	labelNames := append(slices.Clone(baseLabels), tagLabels(cfg.Tags)...)
+	newCache := []prometheus.Metric{}
+	newSamples := []metrics.Sample{}
+	for _, target := range targets {
+		for _, collected := range metricsForTarget(descs, labelNames, cfg, target) {
+			newCache = append(newCache, collected.metric)
+			newSamples = append(newSamples, collected.sample)
+		}
 	}
+	return newCache, newSamples
+}
+
+func (e *RDSExporter) updateCache(ctx context.Context, logger *slog.Logger) {
+	start := time.Now()
+	snap := e.snapshot.Load()
+	cfg := snap.cfg
+
+	targetsChan := make(chan discovery.RDSTarget, 100)
+	errChan := make(chan error, 1)
 	go func() {
-		wg.Wait()
-		close(metricsChan)
+		errChan <- e.discoverer.Discover(ctx, discovery.Filter{
+			RegionAllowed: cfg.RegionAllowed,
+			Tags:          cfg.Tags,
+			AssumeRoleARN: cfg.AssumeRoleARN,
+		}, targetsChan)
+		close(targetsChan)
 	}()
-	newCache := []prometheus.Metric{}
-	for metric := range metricsChan {
-		newCache = append(newCache, metric)
+
+	var targets []discovery.RDSTarget
+	for target := range targetsChan {
+		targets = append(targets, target)
+	}
+	err := <-errChan
+	if err != nil {
+		logger.Error("Error discovering RDS instances", "error", err)
+		scrapeErrorsTotal.WithLabelValues("").Inc()
+	}
+	if reporter, ok := e.discoverer.(discovery.RegionErrorReporter); ok {
+		for region, count := range reporter.RegionErrors() {
+			scrapeErrorsTotal.WithLabelValues(region).Add(float64(count))
+		}
+	}
+
+	// A hard discovery error with nothing discovered (e.g. ListRegions or
+	// GetResources itself got throttled) means this round found nothing, not
+	// that every instance disappeared. Keep serving and persisting whatever
+	// the previous round produced instead of blanking the cache and the
+	// durable store, and don't claim a success that didn't happen.
+	if err != nil && len(targets) == 0 {
+		e.lastDuration.Store(time.Since(start))
+		return
 	}
+
+	newCache, newSamples := buildCacheFromTargets(snap.descs, cfg, targets)
+
 	e.mu.Lock()
 	e.cache = newCache
-	e.lastUpdate = time.Now()
 	e.mu.Unlock()
+
+	if e.remoteWrite != nil {
+		e.remoteWrite.Enqueue(newSamples)
+	}
+
+	now := time.Now()
+	if e.store != nil {
+		if err := e.store.Put(ctx, newSamples, now); err != nil {
+			logger.Error("Error persisting cache to secondary store", "error", err)
+		}
+	}
+
+	e.lastDuration.Store(time.Since(start))
+	e.lastSuccess.Store(now)
+	e.ready.Store(true)
 }
 
-func (e *RDSExporter) collectRegionMetrics(regionName string, ch chan<- prometheus.Metric) {
-	rdsClient := rds.NewFromConfig(e.sdkConfig, func(o *rds.Options) { o.Region = regionName })
-	var marker *string
+// runContinuousCache runs a continuous discoverer's incremental stream,
+// rebuilding the cache after every event rather than waiting for a full
+// scan to complete.
+func (e *RDSExporter) runContinuousCache(ctx context.Context, logger *slog.Logger) {
+	cfg := e.snapshot.Load().cfg
+	targetsChan := make(chan discovery.RDSTarget, 100)
+
+	go func() {
+		if err := e.discoverer.Discover(ctx, discovery.Filter{
+			RegionAllowed: cfg.RegionAllowed,
+			Tags:          cfg.Tags,
+			AssumeRoleARN: cfg.AssumeRoleARN,
+		}, targetsChan); err != nil && ctx.Err() == nil {
+			logger.Error("Error running continuous discovery", "error", err)
+		}
+		close(targetsChan)
+	}()
+
+	byARN := map[string]discovery.RDSTarget{}
 	for {
-		output, err := rdsClient.DescribeDBInstances(context.TODO(), &rds.DescribeDBInstancesInput{Marker: marker})
-		if err != nil {
-			log.Printf("Couldn't list RDS instances in region %s : %v", regionName, err)
-			break
-		}
-		//{"dimension_DBInstanceIdentifier", "az", "secondary_az", "storage_type", "region", "name", "db_instance_class", "engine"}
-		for _, instance := range output.DBInstances {
-			labels := []string{}
-			labels = append(labels, *instance.DBInstanceIdentifier)
-			labels = append(labels, *instance.AvailabilityZone)
-			if instance.SecondaryAvailabilityZone != nil {
-				labels = append(labels, *instance.SecondaryAvailabilityZone)
-			} else {
-				labels = append(labels, "")
-			}
-			labels = append(labels, *instance.StorageType)
-			labels = append(labels, regionName)
-			labels = append(labels, *instance.DBInstanceClass)
-			labels = append(labels, *instance.Engine)
-
-			// Build tags map
-			tagMap := make(map[string]string)
-			for _, tag := range tags {
-				tagMap[tag] = ""
+		select {
+		case <-ctx.Done():
+			return
+		case target, ok := <-targetsChan:
+			if !ok {
+				return
 			}
-			// fetch tags
-			tagsOutput, err := rdsClient.ListTagsForResource(context.TODO(), &rds.ListTagsForResourceInput{
-				ResourceName: instance.DBInstanceArn,
-			})
-			if err != nil {
-				log.Printf("Error listing tags for RDS instance %s : %v", *instance.DBInstanceArn, err)
+			if target.Deleted {
+				removeTarget(byARN, target)
 			} else {
-				for _, tag := range tagsOutput.TagList {
-					if tag.Key != nil && tag.Value != nil && slices.Contains(tags, *tag.Key) {
-						tagMap[*tag.Key] = *tag.Value
-					}
-				}
+				byARN[target.ARN] = target
 			}
+			e.rebuildCacheFromTargets(ctx, byARN, logger)
+		}
+	}
+}
 
-			// Add tags in correct order to labels
-			for _, tag := range tags {
-				labels = append(labels, tagMap[tag])
-			}
-			// New metrics
-			if instance.AllocatedStorage != nil {
-				metric := prometheus.MustNewConstMetric(allocatedStorageDesc, prometheus.GaugeValue, float64(*instance.AllocatedStorage), labels...)
-				e.cache = append(e.cache, metric)
-				ch <- metric
-			}
-			if instance.MaxAllocatedStorage != nil {
-				metric := prometheus.MustNewConstMetric(maxAllocatedStorageDesc, prometheus.GaugeValue, float64(*instance.MaxAllocatedStorage), labels...)
-				e.cache = append(e.cache, metric)
-				ch <- metric
-			}
-			if instance.Iops != nil {
-				metric := prometheus.MustNewConstMetric(iopsDesc, prometheus.GaugeValue, float64(*instance.Iops), labels...)
-				e.cache = append(e.cache, metric)
-				ch <- metric
-			}
-			if instance.StorageThroughput != nil {
-				metric := prometheus.MustNewConstMetric(storageThroughputDesc, prometheus.GaugeValue, float64(*instance.StorageThroughput), labels...)
-				e.cache = append(e.cache, metric)
-				ch <- metric
-			}
+// removeTarget drops deleted's entry from byARN. A deletion tombstone from
+// EventBridge rarely knows the ARN (the describe that would've supplied it
+// is what just failed), so fall back to matching on region+identifier.
+func removeTarget(byARN map[string]discovery.RDSTarget, deleted discovery.RDSTarget) {
+	if deleted.ARN != "" {
+		delete(byARN, deleted.ARN)
+		return
+	}
+	for arn, target := range byARN {
+		if target.Region == deleted.Region && target.Identifier == deleted.Identifier {
+			delete(byARN, arn)
+			return
+		}
+	}
+}
+
+func (e *RDSExporter) rebuildCacheFromTargets(ctx context.Context, byARN map[string]discovery.RDSTarget, logger *slog.Logger) {
+	start := time.Now()
+	snap := e.snapshot.Load()
+
+	targets := make([]discovery.RDSTarget, 0, len(byARN))
+	for _, target := range byARN {
+		targets = append(targets, target)
+	}
+	newCache, newSamples := buildCacheFromTargets(snap.descs, snap.cfg, targets)
+
+	e.mu.Lock()
+	e.cache = newCache
+	e.mu.Unlock()
+
+	if e.remoteWrite != nil {
+		e.remoteWrite.Enqueue(newSamples)
+	}
+
+	now := time.Now()
+	if e.store != nil {
+		if err := e.store.Put(ctx, newSamples, now); err != nil {
+			logger.Error("Error persisting cache to secondary store", "error", err)
+		}
+	}
+
+	e.lastDuration.Store(time.Since(start))
+	e.lastSuccess.Store(now)
+	e.ready.Store(true)
+}
+
+// newDiscoverer builds the discovery strategy selected by cfg.Kind.
+func newDiscoverer(sdkConfig aws.Config, cfg appconfig.DiscoveryConfig, logger *slog.Logger) (discovery.Discoverer, error) {
+	switch cfg.Kind {
+	case "", "walk":
+		return discovery.NewWalkDiscoverer(sdkConfig, logger), nil
+	case "tagging":
+		if len(cfg.Tagging.Tags) == 0 {
+			return nil, fmt.Errorf("discovery.tagging.tags is required for kind: tagging")
+		}
+		return discovery.NewTaggingDiscoverer(sdkConfig, cfg.Tagging.Tags, logger), nil
+	case "static":
+		if cfg.Static.FilePath == "" {
+			return nil, fmt.Errorf("discovery.static.file_path is required for kind: static")
 		}
-		if output.Marker == nil {
-			break
-		} else {
-			marker = output.Marker
+		return discovery.NewStaticDiscovererFromFile(cfg.Static.FilePath)
+	case "eventbridge":
+		if cfg.EventBridge.QueueURL == "" {
+			return nil, fmt.Errorf("discovery.eventbridge.queue_url is required for kind: eventbridge")
 		}
+		return discovery.NewEventBridgeDiscoverer(sdkConfig, cfg.EventBridge.QueueURL, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown discovery kind %q", cfg.Kind)
+	}
+}
+
+// newMetricStore builds the secondary cache selected by cfg.Kind.
+func newMetricStore(cfg appconfig.PersistenceConfig) (store.MetricStore, error) {
+	switch cfg.Kind {
+	case "", "memory":
+		return store.NewMemoryStore(), nil
+	case "file":
+		if cfg.FilePath == "" {
+			return nil, fmt.Errorf("persistence.file_path is required for kind: file")
+		}
+		return store.NewFileStore(cfg.FilePath), nil
+	case "redis":
+		if cfg.Redis.Addr == "" {
+			return nil, fmt.Errorf("persistence.redis.addr is required for kind: redis")
+		}
+		client := redis.NewClient(&redis.Options{Addr: cfg.Redis.Addr})
+		return store.NewRedisStore(client, cfg.Redis.Namespace, cfg.Redis.TTL), nil
+	default:
+		return nil, fmt.Errorf("unknown persistence kind %q", cfg.Kind)
 	}
 }
 
 func main() {
 	listenPort := flag.String("port", "6999", "Exporter listen port")
-	cacheTTL := flag.Duration("cache_ttl", time.Hour, "Cache TTL")
-	ctx, _ := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
-	sdkConfig, err := config.LoadDefaultConfig(ctx)
+	refreshInterval := flag.Duration("refresh_interval", 0, "Interval between background cache refreshes (falls back to RDS_REFRESH_INTERVAL, then the config file's scrape_interval, then 1h)")
+	configFile := flag.String("config.file", "", "Path to the YAML config file (regions, tags, metric selection); hot-reloaded on change")
+	logLevel := flag.String("log.level", utils.LookupEnv("LOG_LEVEL", "info"), "Log level: debug, info, warn, error")
+	logDedupeWindow := flag.Duration("log.dedupe_window", utils.LookupDuration("LOG_DEDUPE_WINDOW", time.Minute), "Suppress identical log lines repeated within this window (0 disables)")
 	flag.Parse()
 
-	logger := log.WithFields(log.Fields{"app": "rds-exporter"})
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger, err := utils.InitLogging("rds-exporter", *logLevel, *logDedupeWindow)
+	if err != nil {
+		slog.Error("unable to init logging", "error", err)
+		os.Exit(1)
+	}
+
+	sdkConfig, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		logger.Error("unable to load SDK config", "error", err)
+		os.Exit(1)
+	}
+
+	cfg := appconfig.Default()
+	if *configFile != "" {
+		cfg, err = appconfig.Load(*configFile)
+		if err != nil {
+			logger.Error("unable to load config file", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	interval := *refreshInterval
+	if interval == 0 {
+		interval = utils.LookupDuration("RDS_REFRESH_INTERVAL", 0)
+	}
+	if interval == 0 {
+		interval = cfg.ScrapeInterval
+	}
+	if interval == 0 {
+		interval = time.Hour
+	}
+
+	metricStore, err := newMetricStore(cfg.Persistence)
+	if err != nil {
+		logger.Error("unable to build metric store", "error", err)
+		os.Exit(1)
+	}
 
+	discoverer, err := newDiscoverer(sdkConfig, cfg.Discovery, logger)
 	if err != nil {
-		logger.Fatalf("unable to load SDK config: %v", err)
+		logger.Error("unable to build discoverer", "error", err)
+		os.Exit(1)
 	}
-	exporter := NewRDSExporter(sdkConfig, cacheTTL)
+
+	exporter := NewRDSExporter(sdkConfig, cfg, metricStore, discoverer)
 	prometheus.MustRegister(exporter)
+	exporter.Warm(ctx, logger)
+
+	if exporter.remoteWrite != nil {
+		go exporter.remoteWrite.Run(ctx, logger)
+	}
+
+	go exporter.runCacheLoop(ctx, interval, logger)
+
+	if *configFile != "" {
+		// Watcher.Run loads the file itself and passes the result to this
+		// callback, which ignores it; the reload outcome (success/failure
+		// counters, descriptor rebuild) always goes through exporter.Reload
+		// so there's exactly one code path for applying a config, whether
+		// triggered by fsnotify or by POST /-/reload.
+		watcher := appconfig.NewWatcher(*configFile, func(_ *appconfig.Config, _ error) {
+			if err := exporter.Reload(*configFile, logger); err != nil {
+				logger.Error("Config watcher reload failed", "error", err)
+			}
+		}, logger)
+		go func() {
+			if err := watcher.Run(ctx); err != nil {
+				logger.Error("Config watcher stopped", "error", err)
+			}
+		}()
+	}
+
 	http.Handle("/metrics", promhttp.Handler())
-	logger.Println("Listening on :" + *listenPort)
+	logger.Info("Listening on :" + *listenPort)
 	http.HandleFunc("/readyz", func(writer http.ResponseWriter, request *http.Request) {
+		if !exporter.Ready() {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			writer.Write([]byte(`{"status":"waiting for first cache refresh"}`))
+			return
+		}
 		writer.WriteHeader(200)
 		writer.Write([]byte(`{"status":"OK"}`))
 	})
@@ -228,19 +642,26 @@ func main() {
 		writer.WriteHeader(200)
 		writer.Write([]byte(`{"status":"OK"}`))
 	})
+	http.HandleFunc("/-/reload", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			writer.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if *configFile == "" {
+			writer.WriteHeader(http.StatusBadRequest)
+			writer.Write([]byte(`{"status":"no -config.file configured"}`))
+			return
+		}
+		if err := exporter.Reload(*configFile, logger); err != nil {
+			writer.WriteHeader(http.StatusInternalServerError)
+			writer.Write([]byte(`{"status":"` + err.Error() + `"}`))
+			return
+		}
+		writer.WriteHeader(200)
+		writer.Write([]byte(`{"status":"OK"}`))
+	})
 	if err := http.ListenAndServe(":"+*listenPort, nil); err != nil {
-		logger.Fatalf("Error starting metric server: %s", err)
+		logger.Error("Error starting metric server", "error", err)
+		os.Exit(1)
 	}
 }
-
-//    TODO:
-// Я так бегло глянул, чуть накидал комментов, чуть позже детальнее ещё гляну
-// глянь пока на комменты ну и докинь ещё докерфайл плиз - по аналогии с остальными
-// заодно можно и сборку прикрутить чтобы сразу всё было
-// по архитектуре - что я бы ещё сделал
-// я бы не стал городить логику с проверкой времени протухания кеша
-// я бы ещё при запуске main запускал бы горутинку updateCache() по тику таймера ну или по forever-циклу со слипом - неважно сколько данные пролежали в кеше, главное что их нужно обновлять каждые n-тиков времени
-// из метода коллект тогда можно всё вытащить, кроме отдачи метрик
-// это кмк немного прозрачнее - те стартует приложенька ну и в фоне потихоньку апдейтит метрики
-//
-// ну и ещё допилить классику - сделать readyz / healthz эндпоинт, логи обвернуть в json
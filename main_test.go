@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+
+	appconfig "github.com/deeb00/rdscli_exporter/pkg/config"
+	"github.com/deeb00/rdscli_exporter/pkg/discovery"
+	"github.com/deeb00/rdscli_exporter/pkg/metrics"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int32ptr(v int32) *int32 { return &v }
+
+func testTarget() discovery.RDSTarget {
+	return discovery.RDSTarget{
+		Region:           "us-east-1",
+		ARN:              "arn:aws:rds:us-east-1:111:db:my-db",
+		Identifier:       "my-db",
+		AvailabilityZone: "us-east-1a",
+		StorageType:      "gp3",
+		Class:            "db.r6g.large",
+		Engine:           "postgres",
+		Tags:             map[string]string{"team": "infra"},
+		AllocatedStorage: int32ptr(100),
+		IOPS:             int32ptr(3000),
+	}
+}
+
+func TestBuildCacheFromTargetsAndSampleToMetricRoundTrip(t *testing.T) {
+	cfg := &appconfig.Config{Tags: []string{"team"}}
+	descs := buildMetricDescs(cfg.Tags)
+
+	metricsOut, samples := buildCacheFromTargets(descs, cfg, []discovery.RDSTarget{testTarget()})
+
+	// allocated_storage and iops are set on the target; max_allocated_storage
+	// and storage_throughput are nil and should be skipped, not emitted as 0.
+	require.Len(t, metricsOut, 2)
+	require.Len(t, samples, 2)
+
+	for i, sample := range samples {
+		rebuilt, err := sampleToMetric(descs, cfg.Tags, sample)
+		require.NoError(t, err)
+
+		var want, got dto.Metric
+		require.NoError(t, metricsOut[i].Write(&want))
+		require.NoError(t, rebuilt.Write(&got))
+		assert.Equal(t, want.String(), got.String())
+	}
+}
+
+func TestSampleToMetricUnknownName(t *testing.T) {
+	descs := buildMetricDescs(nil)
+	_, err := sampleToMetric(descs, nil, metrics.Sample{Name: "not_a_real_metric"})
+	assert.Error(t, err)
+}
+
+func TestMetricsForTargetSkipsDisabledAndNilMetrics(t *testing.T) {
+	disabled := false
+	cfg := &appconfig.Config{Metrics: appconfig.MetricsConfig{IOPS: &disabled}}
+	descs := buildMetricDescs(cfg.Tags)
+	labelNames := append([]string{}, baseLabels...)
+
+	collected := metricsForTarget(descs, labelNames, cfg, testTarget())
+
+	names := make([]string, 0, len(collected))
+	for _, c := range collected {
+		names = append(names, c.sample.Name)
+	}
+	assert.ElementsMatch(t, []string{"aws_rds_allocated_storage"}, names)
+}
+
+func TestRemoveTarget(t *testing.T) {
+	byARN := map[string]discovery.RDSTarget{
+		"arn:1": {ARN: "arn:1", Region: "us-east-1", Identifier: "db-1"},
+		"arn:2": {ARN: "arn:2", Region: "us-east-1", Identifier: "db-2"},
+	}
+
+	// Deletion with a known ARN removes exactly that entry.
+	removeTarget(byARN, discovery.RDSTarget{ARN: "arn:1"})
+	assert.NotContains(t, byARN, "arn:1")
+	assert.Contains(t, byARN, "arn:2")
+
+	// A tombstone without an ARN (the common EventBridge case) falls back to
+	// matching on region+identifier.
+	removeTarget(byARN, discovery.RDSTarget{Region: "us-east-1", Identifier: "db-2"})
+	assert.Empty(t, byARN)
+}
+
+func TestBuildMetricDescsLabelsIncludeTags(t *testing.T) {
+	descs := buildMetricDescs([]string{"team", "env"})
+	assert.Contains(t, descs.iops.String(), "tag_team")
+	assert.Contains(t, descs.iops.String(), "tag_env")
+}
@@ -0,0 +1,223 @@
+// Package config loads and hot-reloads the exporter's YAML configuration:
+// which regions to scan, which RDS tags to promote to labels, which metrics
+// are enabled, and the scrape interval.
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/deeb00/rdscli_exporter/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// RegionConfig optionally restricts which regions are scanned and how.
+type RegionConfig struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+	// AssumeRoleARNs maps a region, or a wildcard pattern matched the same
+	// way as Allow/Deny, to the role the exporter should assume when
+	// scanning it. A "*" entry is the fallback applied to any region with
+	// no more specific match.
+	AssumeRoleARNs map[string]string `yaml:"assume_role_arns"`
+}
+
+// MetricsConfig toggles individual metric families on or off.
+type MetricsConfig struct {
+	AllocatedStorage    *bool `yaml:"allocated_storage"`
+	MaxAllocatedStorage *bool `yaml:"max_allocated_storage"`
+	IOPS                *bool `yaml:"iops"`
+	StorageThroughput   *bool `yaml:"storage_throughput"`
+}
+
+// RemoteWriteEndpoint is a single remote-write push target.
+type RemoteWriteEndpoint struct {
+	URL           string        `yaml:"url"`
+	BearerToken   string        `yaml:"bearer_token"`
+	BasicAuthUser string        `yaml:"basic_auth_user"`
+	BasicAuthPass string        `yaml:"basic_auth_pass"`
+	Timeout       time.Duration `yaml:"timeout"`
+}
+
+// RemoteWriteConfig configures pushing the cache to one or more Prometheus
+// remote-write endpoints on every cache refresh, as an alternative (or
+// addition) to serving /metrics.
+type RemoteWriteConfig struct {
+	Endpoints         []RemoteWriteEndpoint `yaml:"endpoints"`
+	ExternalLabels    map[string]string     `yaml:"external_labels"`
+	QueueSize         int                   `yaml:"queue_size"`
+	MaxSamplesPerSend int                   `yaml:"max_samples_per_send"`
+	RetryInitialDelay time.Duration         `yaml:"retry_initial_delay"`
+	RetryMaxDelay     time.Duration         `yaml:"retry_max_delay"`
+}
+
+// RedisPersistenceConfig configures the Redis secondary cache.
+type RedisPersistenceConfig struct {
+	Addr      string        `yaml:"addr"`
+	Namespace string        `yaml:"namespace"`
+	TTL       time.Duration `yaml:"ttl"`
+}
+
+// PersistenceConfig selects and configures the exporter's secondary cache,
+// written after each refresh and read only at startup to warm the in-memory
+// view. It is not consulted when a region's DescribeDBInstances call fails.
+// Kind is one of "memory" (the default, i.e. no persistence), "file", or
+// "redis".
+type PersistenceConfig struct {
+	Kind     string                 `yaml:"kind"`
+	FilePath string                 `yaml:"file_path"`
+	Redis    RedisPersistenceConfig `yaml:"redis"`
+}
+
+// TaggingDiscoveryConfig configures the Resource Groups Tagging API
+// discoverer: only instances matching every one of these tag keys are
+// scraped. An empty value list matches any value for that key; otherwise
+// any one of the listed values matches.
+type TaggingDiscoveryConfig struct {
+	Tags map[string][]string `yaml:"tags"`
+}
+
+// StaticDiscoveryConfig configures the static-file discoverer.
+type StaticDiscoveryConfig struct {
+	FilePath string `yaml:"file_path"`
+}
+
+// EventBridgeDiscoveryConfig configures the EventBridge/SQS discoverer.
+type EventBridgeDiscoveryConfig struct {
+	QueueURL string `yaml:"queue_url"`
+}
+
+// DiscoveryConfig selects how the exporter finds RDS instances to scrape.
+// Kind is one of "walk" (the default: list every region, then
+// DescribeDBInstances in each), "tagging" (Resource Groups Tagging API),
+// "static" (a fixed file, for tests), or "eventbridge" (incremental updates
+// from RDS lifecycle events via EventBridge and SQS).
+type DiscoveryConfig struct {
+	Kind        string                     `yaml:"kind"`
+	Tagging     TaggingDiscoveryConfig     `yaml:"tagging"`
+	Static      StaticDiscoveryConfig      `yaml:"static"`
+	EventBridge EventBridgeDiscoveryConfig `yaml:"eventbridge"`
+}
+
+// Config is the top-level shape of the YAML config file.
+type Config struct {
+	ScrapeInterval time.Duration     `yaml:"scrape_interval"`
+	Regions        RegionConfig      `yaml:"regions"`
+	Tags           []string          `yaml:"tags"`
+	Metrics        MetricsConfig     `yaml:"metrics"`
+	RemoteWrite    RemoteWriteConfig `yaml:"remote_write"`
+	Persistence    PersistenceConfig `yaml:"persistence"`
+	Discovery      DiscoveryConfig   `yaml:"discovery"`
+}
+
+func enabled(b *bool) bool {
+	return b == nil || *b
+}
+
+// Enabled reports whether the named metric family is enabled. Unknown names
+// and unset flags both default to enabled.
+func (c *Config) Enabled(metric string) bool {
+	switch metric {
+	case "allocated_storage":
+		return enabled(c.Metrics.AllocatedStorage)
+	case "max_allocated_storage":
+		return enabled(c.Metrics.MaxAllocatedStorage)
+	case "iops":
+		return enabled(c.Metrics.IOPS)
+	case "storage_throughput":
+		return enabled(c.Metrics.StorageThroughput)
+	default:
+		return true
+	}
+}
+
+// RegionAllowed reports whether region should be scanned under this config.
+// A deny match always wins; otherwise an empty allow list permits everything.
+func (c *Config) RegionAllowed(region string) bool {
+	for _, pattern := range c.Regions.Deny {
+		if utils.WildcardMatch(pattern, region) {
+			return false
+		}
+	}
+	if len(c.Regions.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range c.Regions.Allow {
+		if utils.WildcardMatch(pattern, region) {
+			return true
+		}
+	}
+	return false
+}
+
+// AssumeRoleARN returns the role to assume when scanning region, if one is
+// configured. An exact match in AssumeRoleARNs wins over a wildcard
+// pattern, and any other matching pattern wins over "*"; which of several
+// matching patterns other than "*" is used is not defined. An empty return
+// means use the exporter's own credentials.
+func (c *Config) AssumeRoleARN(region string) string {
+	if arn, ok := c.Regions.AssumeRoleARNs[region]; ok {
+		return arn
+	}
+	for pattern, arn := range c.Regions.AssumeRoleARNs {
+		if pattern != "*" && utils.WildcardMatch(pattern, region) {
+			return arn
+		}
+	}
+	return c.Regions.AssumeRoleARNs["*"]
+}
+
+const (
+	defaultRemoteWriteQueueSize         = 10000
+	defaultRemoteWriteMaxSamplesPerSend = 500
+	defaultRemoteWriteRetryInitialDelay = 500 * time.Millisecond
+	defaultRemoteWriteRetryMaxDelay     = 30 * time.Second
+)
+
+// Default returns the config used when no -config.file is given.
+func Default() *Config {
+	return &Config{
+		ScrapeInterval: time.Hour,
+		Tags:           []string{"purpose", "team", "region", "environment"},
+		RemoteWrite: RemoteWriteConfig{
+			QueueSize:         defaultRemoteWriteQueueSize,
+			MaxSamplesPerSend: defaultRemoteWriteMaxSamplesPerSend,
+			RetryInitialDelay: defaultRemoteWriteRetryInitialDelay,
+			RetryMaxDelay:     defaultRemoteWriteRetryMaxDelay,
+		},
+	}
+}
+
+// Load reads and parses the YAML config file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file %s: %w", path, err)
+	}
+
+	cfg := Default()
+	cfg.ScrapeInterval = 0
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	if cfg.ScrapeInterval == 0 {
+		cfg.ScrapeInterval = time.Hour
+	}
+	if len(cfg.Tags) == 0 {
+		cfg.Tags = Default().Tags
+	}
+	if cfg.RemoteWrite.QueueSize == 0 {
+		cfg.RemoteWrite.QueueSize = utils.LookupInt("REMOTE_WRITE_QUEUE_SIZE", defaultRemoteWriteQueueSize)
+	}
+	if cfg.RemoteWrite.MaxSamplesPerSend == 0 {
+		cfg.RemoteWrite.MaxSamplesPerSend = utils.LookupInt("REMOTE_WRITE_MAX_SAMPLES_PER_SEND", defaultRemoteWriteMaxSamplesPerSend)
+	}
+	if cfg.RemoteWrite.RetryInitialDelay == 0 {
+		cfg.RemoteWrite.RetryInitialDelay = utils.LookupDuration("REMOTE_WRITE_RETRY_INITIAL_DELAY", defaultRemoteWriteRetryInitialDelay)
+	}
+	if cfg.RemoteWrite.RetryMaxDelay == 0 {
+		cfg.RemoteWrite.RetryMaxDelay = utils.LookupDuration("REMOTE_WRITE_RETRY_MAX_DELAY", defaultRemoteWriteRetryMaxDelay)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegionAllowed(t *testing.T) {
+	testCases := []struct {
+		name     string
+		regions  RegionConfig
+		region   string
+		expected bool
+	}{
+		{
+			name:     "empty allow list permits everything",
+			regions:  RegionConfig{},
+			region:   "us-east-1",
+			expected: true,
+		},
+		{
+			name:     "allow list restricts to matching regions",
+			regions:  RegionConfig{Allow: []string{"us-*"}},
+			region:   "eu-west-1",
+			expected: false,
+		},
+		{
+			name:     "allow list permits matching regions",
+			regions:  RegionConfig{Allow: []string{"us-*"}},
+			region:   "us-east-1",
+			expected: true,
+		},
+		{
+			name:     "deny always wins over allow",
+			regions:  RegionConfig{Allow: []string{"us-*"}, Deny: []string{"us-east-1"}},
+			region:   "us-east-1",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{Regions: tc.regions}
+			assert.Equal(t, tc.expected, cfg.RegionAllowed(tc.region))
+		})
+	}
+}
+
+func TestAssumeRoleARN(t *testing.T) {
+	testCases := []struct {
+		name     string
+		arns     map[string]string
+		region   string
+		expected string
+	}{
+		{
+			name:     "no entries means no role",
+			arns:     nil,
+			region:   "us-east-1",
+			expected: "",
+		},
+		{
+			name:     "exact match wins over wildcard",
+			arns:     map[string]string{"us-east-1": "arn:aws:iam::111:role/exact", "us-*": "arn:aws:iam::111:role/wildcard"},
+			region:   "us-east-1",
+			expected: "arn:aws:iam::111:role/exact",
+		},
+		{
+			name:     "wildcard matches when no exact entry",
+			arns:     map[string]string{"us-*": "arn:aws:iam::111:role/wildcard"},
+			region:   "us-west-2",
+			expected: "arn:aws:iam::111:role/wildcard",
+		},
+		{
+			name:     "fallback applies to any unmatched region",
+			arns:     map[string]string{"*": "arn:aws:iam::111:role/fallback"},
+			region:   "eu-west-1",
+			expected: "arn:aws:iam::111:role/fallback",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{Regions: RegionConfig{AssumeRoleARNs: tc.arns}}
+			assert.Equal(t, tc.expected, cfg.AssumeRoleARN(tc.region))
+		})
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("regions:\n  allow: [\"us-east-1\"]\n"), 0o644))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, cfg.ScrapeInterval)
+	assert.Equal(t, Default().Tags, cfg.Tags)
+	assert.Equal(t, []string{"us-east-1"}, cfg.Regions.Allow)
+	assert.Equal(t, defaultRemoteWriteQueueSize, cfg.RemoteWrite.QueueSize)
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "does-not-exist.yaml"))
+	require.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher reloads the config file whenever it changes on disk and invokes
+// onReload with the new config. On parse failure the previous config keeps
+// serving; onReload is still called, with a nil config and the error, so the
+// caller can bump a failure counter.
+type Watcher struct {
+	path     string
+	onReload func(cfg *Config, err error)
+	logger   *slog.Logger
+}
+
+// NewWatcher creates a Watcher for path. Call Run to start watching.
+func NewWatcher(path string, onReload func(cfg *Config, err error), logger *slog.Logger) *Watcher {
+	return &Watcher{path: path, onReload: onReload, logger: logger}
+}
+
+// Run watches the config file for changes until ctx is cancelled. Editors
+// like vim replace the file on save (a RENAME event followed by the new file
+// appearing under the old name), which drops the original inode from the
+// watch list, so Run re-adds the watch after every event.
+func (w *Watcher) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(w.path); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.logger.Error("config watcher error", "error", err)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+				continue
+			}
+			// Re-add the watch in case the file was replaced (vim's
+			// rename-then-create dance drops the inode we were watching).
+			_ = watcher.Remove(w.path)
+			if err := watcher.Add(w.path); err != nil {
+				w.logger.Error("re-adding config watch", "error", err)
+			}
+			if event.Op&fsnotify.Remove != 0 {
+				continue
+			}
+			cfg, err := Load(w.path)
+			w.onReload(cfg, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Package discovery finds the RDS instances the exporter should scrape. It
+// replaces a single hardcoded ListRegions-then-DescribeDBInstances walk with
+// a pluggable Discoverer interface, so a faster or more targeted source
+// (tag filters, RDS lifecycle events, a static list for tests) can be
+// dropped in without touching the Prometheus collector.
+package discovery
+
+import "context"
+
+// RDSTarget is a single RDS instance to scrape, as produced by a
+// Discoverer. It carries everything the collector needs to build metrics,
+// so a Discoverer backed by a tag-filtered lookup or a single-instance
+// describe (rather than a full per-region walk) works exactly the same way.
+//
+// A target with Deleted set is a tombstone, not an instance to scrape: it
+// tells an incremental collector (see EventBridgeDiscoverer) to drop
+// Region/Identifier (or ARN, if known) from its view instead of adding or
+// updating an entry. Snapshot discoverers never emit one, since a full
+// rescan already drops anything it doesn't see.
+type RDSTarget struct {
+	Region                    string
+	ARN                       string
+	Identifier                string
+	AvailabilityZone          string
+	SecondaryAvailabilityZone string
+	StorageType               string
+	Class                     string
+	Engine                    string
+	Tags                      map[string]string
+	Deleted                   bool
+
+	AllocatedStorage    *int32
+	MaxAllocatedStorage *int32
+	IOPS                *int32
+	StorageThroughput   *int32
+}
+
+// Filter narrows which instances a Discoverer returns. It is rebuilt from
+// the live config on every call since regions, tags, and the assumed role
+// can all change via config hot reload. AssumeRoleARN is per-region (some
+// setups scan accounts/regions that need different credentials), so it's a
+// lookup function rather than a single string.
+type Filter struct {
+	RegionAllowed func(region string) bool
+	Tags          []string
+	AssumeRoleARN func(region string) string
+}
+
+// Discoverer produces the current set of RDS instances to scrape. Snapshot
+// discoverers (Walk, Tagging, Static) send the complete set on out and
+// return; the collector re-invokes them on its refresh interval. Continuous
+// discoverers (EventBridge) block, sending incremental updates as they
+// arrive, and only return once ctx is done — see Continuous.
+type Discoverer interface {
+	Discover(ctx context.Context, filter Filter, out chan<- RDSTarget) error
+
+	// Continuous reports whether Discover streams incremental updates for
+	// the lifetime of ctx instead of completing a full scan. The collector
+	// runs a continuous Discoverer once, in the background, rather than
+	// driving it off the refresh ticker.
+	Continuous() bool
+}
+
+// StaleReporter is implemented by discoverers that track per-region scrape
+// freshness, so the collector can surface
+// aws_rds_exporter_cache_stale_seconds. Not every Discoverer can offer this
+// (e.g. Tagging and Static have no notion of "this region failed").
+type StaleReporter interface {
+	StaleSeconds() map[string]float64
+}
+
+// RegionErrorReporter is implemented by discoverers that track per-region
+// scrape failures, so the collector can increment
+// aws_rds_exporter_scrape_errors_total with a real region label instead of
+// only the aggregate failure recorded against an empty label. RegionErrors
+// drains the counts it returns, so the collector can Add them directly
+// without double-counting on the next call.
+type RegionErrorReporter interface {
+	RegionErrors() map[string]int
+}
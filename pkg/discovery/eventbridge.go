@@ -0,0 +1,115 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// rdsInstanceDeletedEventID is the RDS event code sent when a DB instance is
+// deleted. See the "DB Instance Events" table at
+// https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/USER_Events.Messages.html
+const rdsInstanceDeletedEventID = "RDS-EVENT-0003"
+
+// EventBridgeDiscoverer subscribes to an SQS queue fed by an EventBridge
+// rule on RDS lifecycle events and discovers instances incrementally as
+// they're created, modified, or deleted, instead of re-scanning every
+// region on every refresh.
+type EventBridgeDiscoverer struct {
+	sdkConfig aws.Config
+	queueURL  string
+	logger    *slog.Logger
+}
+
+// NewEventBridgeDiscoverer returns a Discoverer that polls queueURL for RDS
+// lifecycle events.
+func NewEventBridgeDiscoverer(sdkConfig aws.Config, queueURL string, logger *slog.Logger) *EventBridgeDiscoverer {
+	return &EventBridgeDiscoverer{sdkConfig: sdkConfig, queueURL: queueURL, logger: logger}
+}
+
+// Continuous reports true: Discover blocks for the lifetime of ctx, sending
+// one target per RDS event it receives, rather than completing a scan.
+func (d *EventBridgeDiscoverer) Continuous() bool { return true }
+
+// eventBridgeEvent is the subset of an RDS event, as delivered to SQS by an
+// EventBridge rule, that identifies which instance changed.
+type eventBridgeEvent struct {
+	Region string `json:"region"`
+	Detail struct {
+		SourceIdentifier string `json:"SourceIdentifier"`
+		SourceType       string `json:"SourceType"`
+		EventID          string `json:"EventID"`
+	} `json:"detail"`
+}
+
+func (d *EventBridgeDiscoverer) Discover(ctx context.Context, filter Filter, out chan<- RDSTarget) error {
+	sqsClient := sqs.NewFromConfig(d.sdkConfig)
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		output, err := sqsClient.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(d.queueURL),
+			MaxNumberOfMessages: 10,
+			WaitTimeSeconds:     20,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			d.logger.Error("Error receiving RDS events from SQS", "error", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, message := range output.Messages {
+			d.handleMessage(ctx, message, filter, out)
+			if _, err := sqsClient.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String(d.queueURL),
+				ReceiptHandle: message.ReceiptHandle,
+			}); err != nil {
+				d.logger.Error("Error deleting processed RDS event", "error", err)
+			}
+		}
+	}
+}
+
+func (d *EventBridgeDiscoverer) handleMessage(ctx context.Context, message sqstypes.Message, filter Filter, out chan<- RDSTarget) {
+	var event eventBridgeEvent
+	if err := json.Unmarshal([]byte(aws.ToString(message.Body)), &event); err != nil {
+		d.logger.Error("Error parsing RDS event", "error", err)
+		return
+	}
+	if event.Detail.SourceType != "DB_INSTANCE" || event.Detail.SourceIdentifier == "" {
+		return
+	}
+	if !filter.RegionAllowed(event.Region) {
+		return
+	}
+
+	sdkConfig := regionSDKConfig(d.sdkConfig, event.Region, filter.AssumeRoleARN(event.Region))
+	target, err := describeDBInstance(ctx, sdkConfig, event.Detail.SourceIdentifier, filter.Tags)
+	if err != nil {
+		// A deletion event's instance is gone by the time we describe it, so
+		// errors.Is catches that race even when EventID doesn't (e.g. a
+		// differently-coded event racing the same disappearance). Either way
+		// this is authoritative that the instance is gone, not just a fetch
+		// failure: tell the collector to drop it instead of leaving its last
+		// known metrics in place forever.
+		if event.Detail.EventID == rdsInstanceDeletedEventID || errors.Is(err, ErrDBInstanceNotFound) {
+			out <- RDSTarget{Region: event.Region, Identifier: event.Detail.SourceIdentifier, Deleted: true}
+			return
+		}
+		d.logger.Warn("Couldn't describe RDS instance from event", "identifier", event.Detail.SourceIdentifier, "error", err)
+		return
+	}
+	out <- target
+}
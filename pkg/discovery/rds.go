@@ -0,0 +1,94 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// ErrDBInstanceNotFound is returned by describeDBInstance when the instance
+// no longer exists, so a caller that cares about deletions (e.g.
+// EventBridgeDiscoverer) can tell "it's gone" apart from "the describe call
+// failed" instead of treating both the same way.
+var ErrDBInstanceNotFound = errors.New("rds instance not found")
+
+// regionSDKConfig returns sdkConfig scoped to region, assuming
+// assumeRoleARN if one is given. Shared by every discoverer that talks to
+// AWS directly, so per-region roles work the same way regardless of how an
+// instance was discovered.
+func regionSDKConfig(sdkConfig aws.Config, region, assumeRoleARN string) aws.Config {
+	cfg := sdkConfig.Copy()
+	cfg.Region = region
+	if assumeRoleARN != "" {
+		stsClient := sts.NewFromConfig(cfg)
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, assumeRoleARN))
+	}
+	return cfg
+}
+
+// describeDBInstance fetches a single instance's details and tags. It's
+// shared by discoverers that learn an instance's identifier before knowing
+// anything else about it (a tag match, an RDS lifecycle event).
+func describeDBInstance(ctx context.Context, sdkConfig aws.Config, identifier string, tags []string) (RDSTarget, error) {
+	rdsClient := rds.NewFromConfig(sdkConfig)
+	output, err := rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(identifier)})
+	if err != nil {
+		var notFound *rdstypes.DBInstanceNotFoundFault
+		if errors.As(err, &notFound) {
+			return RDSTarget{}, fmt.Errorf("%w: %s", ErrDBInstanceNotFound, identifier)
+		}
+		return RDSTarget{}, fmt.Errorf("describing RDS instance %s: %w", identifier, err)
+	}
+	if len(output.DBInstances) == 0 {
+		return RDSTarget{}, fmt.Errorf("%w: %s", ErrDBInstanceNotFound, identifier)
+	}
+	instance := output.DBInstances[0]
+
+	target := RDSTarget{
+		Region:                    sdkConfig.Region,
+		ARN:                       aws.ToString(instance.DBInstanceArn),
+		Identifier:                aws.ToString(instance.DBInstanceIdentifier),
+		AvailabilityZone:          aws.ToString(instance.AvailabilityZone),
+		SecondaryAvailabilityZone: aws.ToString(instance.SecondaryAvailabilityZone),
+		StorageType:               aws.ToString(instance.StorageType),
+		Class:                     aws.ToString(instance.DBInstanceClass),
+		Engine:                    aws.ToString(instance.Engine),
+		AllocatedStorage:          instance.AllocatedStorage,
+		MaxAllocatedStorage:       instance.MaxAllocatedStorage,
+		IOPS:                      instance.Iops,
+		StorageThroughput:         instance.StorageThroughput,
+		Tags:                      make(map[string]string, len(tags)),
+	}
+	for _, tag := range tags {
+		target.Tags[tag] = ""
+	}
+
+	tagsOutput, err := rdsClient.ListTagsForResource(ctx, &rds.ListTagsForResourceInput{ResourceName: instance.DBInstanceArn})
+	if err != nil {
+		return target, fmt.Errorf("listing tags for RDS instance %s: %w", identifier, err)
+	}
+	for _, tag := range tagsOutput.TagList {
+		if tag.Key != nil && tag.Value != nil && slices.Contains(tags, *tag.Key) {
+			target.Tags[*tag.Key] = *tag.Value
+		}
+	}
+	return target, nil
+}
+
+// parseDBInstanceARN extracts the region and DB instance identifier from an
+// RDS instance ARN (arn:aws:rds:REGION:ACCOUNT:db:IDENTIFIER).
+func parseDBInstanceARN(arn string) (region, identifier string, ok bool) {
+	parts := strings.Split(arn, ":")
+	if len(parts) != 7 || parts[0] != "arn" || parts[2] != "rds" || parts[5] != "db" {
+		return "", "", false
+	}
+	return parts[3], parts[6], true
+}
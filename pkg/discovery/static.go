@@ -0,0 +1,51 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// StaticDiscoverer returns a fixed set of targets, configured up front. It
+// exists for tests and for setups where RDS instances are enumerated by
+// some external inventory process rather than discovered live.
+type StaticDiscoverer struct {
+	targets []RDSTarget
+}
+
+// NewStaticDiscoverer returns a Discoverer that emits targets as-is,
+// skipping any whose region the filter rejects.
+func NewStaticDiscoverer(targets []RDSTarget) *StaticDiscoverer {
+	return &StaticDiscoverer{targets: targets}
+}
+
+// NewStaticDiscovererFromFile loads targets from a JSON file containing a
+// list of RDSTarget objects.
+func NewStaticDiscovererFromFile(path string) (*StaticDiscoverer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading static discovery file %s: %w", path, err)
+	}
+	var targets []RDSTarget
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("parsing static discovery file %s: %w", path, err)
+	}
+	return NewStaticDiscoverer(targets), nil
+}
+
+func (d *StaticDiscoverer) Continuous() bool { return false }
+
+func (d *StaticDiscoverer) Discover(ctx context.Context, filter Filter, out chan<- RDSTarget) error {
+	for _, target := range d.targets {
+		if filter.RegionAllowed != nil && !filter.RegionAllowed(target.Region) {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case out <- target:
+		}
+	}
+	return nil
+}
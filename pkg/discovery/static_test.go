@@ -0,0 +1,32 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticDiscovererFiltersByRegion(t *testing.T) {
+	d := NewStaticDiscoverer([]RDSTarget{
+		{Region: "us-east-1", Identifier: "db-1"},
+		{Region: "eu-west-1", Identifier: "db-2"},
+	})
+
+	var got []RDSTarget
+	out := make(chan RDSTarget, 2)
+	filter := Filter{RegionAllowed: func(region string) bool { return region == "us-east-1" }}
+	require.NoError(t, d.Discover(context.Background(), filter, out))
+	close(out)
+	for target := range out {
+		got = append(got, target)
+	}
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "db-1", got[0].Identifier)
+}
+
+func TestStaticDiscovererContinuousIsFalse(t *testing.T) {
+	assert.False(t, NewStaticDiscoverer(nil).Continuous())
+}
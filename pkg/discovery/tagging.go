@@ -0,0 +1,73 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	rgtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+)
+
+// TaggingDiscoverer finds RDS instances via the Resource Groups Tagging API
+// instead of calling DescribeDBInstances for every instance. GetResources
+// filters server-side by tag, so only matching instances are described.
+// GetResources is called in the region of the SDK config only; tagged
+// instances in other regions are not found.
+type TaggingDiscoverer struct {
+	sdkConfig aws.Config
+	filters   []rgtypes.TagFilter
+	logger    *slog.Logger
+}
+
+// NewTaggingDiscoverer returns a Discoverer that only scrapes instances
+// matching tagFilters (tag key -> allowed values; an empty value slice
+// matches any value for that key).
+func NewTaggingDiscoverer(sdkConfig aws.Config, tagFilters map[string][]string, logger *slog.Logger) *TaggingDiscoverer {
+	filters := make([]rgtypes.TagFilter, 0, len(tagFilters))
+	for key, values := range tagFilters {
+		filters = append(filters, rgtypes.TagFilter{Key: aws.String(key), Values: values})
+	}
+	return &TaggingDiscoverer{sdkConfig: sdkConfig, filters: filters, logger: logger}
+}
+
+func (d *TaggingDiscoverer) Continuous() bool { return false }
+
+func (d *TaggingDiscoverer) Discover(ctx context.Context, filter Filter, out chan<- RDSTarget) error {
+	taggingClient := resourcegroupstaggingapi.NewFromConfig(d.sdkConfig)
+
+	var paginationToken *string
+	for {
+		output, err := taggingClient.GetResources(ctx, &resourcegroupstaggingapi.GetResourcesInput{
+			ResourceTypeFilters: []string{"rds:db"},
+			TagFilters:          d.filters,
+			PaginationToken:     paginationToken,
+		})
+		if err != nil {
+			return fmt.Errorf("listing tagged RDS instances: %w", err)
+		}
+
+		for _, mapping := range output.ResourceTagMappingList {
+			arn := aws.ToString(mapping.ResourceARN)
+			region, identifier, ok := parseDBInstanceARN(arn)
+			if !ok || !filter.RegionAllowed(region) {
+				continue
+			}
+
+			sdkConfig := regionSDKConfig(d.sdkConfig, region, filter.AssumeRoleARN(region))
+			target, err := describeDBInstance(ctx, sdkConfig, identifier, filter.Tags)
+			if err != nil {
+				d.logger.Error("Error describing tagged RDS instance", "arn", arn, "error", err)
+				continue
+			}
+			out <- target
+		}
+
+		if output.PaginationToken == nil || *output.PaginationToken == "" {
+			break
+		}
+		paginationToken = output.PaginationToken
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/account"
+	"github.com/aws/aws-sdk-go-v2/service/account/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// regionSnapshot is the last known-good scan of a single region, kept
+// around so a failing DescribeDBInstances call can still serve something.
+type regionSnapshot struct {
+	targets []RDSTarget
+	time    time.Time
+}
+
+// WalkDiscoverer is the original discovery strategy: list every enabled
+// region, then call DescribeDBInstances (and ListTagsForResource) in each
+// one. It's the slowest option but needs no extra AWS permissions beyond
+// what the exporter already has.
+type WalkDiscoverer struct {
+	sdkConfig aws.Config
+	logger    *slog.Logger
+
+	mu        sync.RWMutex
+	cache     map[string]regionSnapshot
+	errCounts map[string]int
+}
+
+// NewWalkDiscoverer returns a Discoverer that walks every enabled region.
+func NewWalkDiscoverer(sdkConfig aws.Config, logger *slog.Logger) *WalkDiscoverer {
+	return &WalkDiscoverer{sdkConfig: sdkConfig, logger: logger, cache: map[string]regionSnapshot{}, errCounts: map[string]int{}}
+}
+
+func (d *WalkDiscoverer) Continuous() bool { return false }
+
+func (d *WalkDiscoverer) Discover(ctx context.Context, filter Filter, out chan<- RDSTarget) error {
+	accountClient := account.NewFromConfig(d.sdkConfig)
+	regionOutput, err := accountClient.ListRegions(ctx, &account.ListRegionsInput{
+		RegionOptStatusContains: []types.RegionOptStatus{types.RegionOptStatusEnabled, types.RegionOptStatusEnabledByDefault}})
+	if err != nil {
+		return fmt.Errorf("listing regions: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, region := range regionOutput.Regions {
+		regionName := *region.RegionName
+		if !filter.RegionAllowed(regionName) {
+			continue
+		}
+		wg.Add(1)
+		go func(regionName string) {
+			defer wg.Done()
+			d.discoverRegion(ctx, regionName, filter, out)
+		}(regionName)
+	}
+	wg.Wait()
+	return nil
+}
+
+// discoverRegion fetches RDS instances for a single region and sends the
+// resulting targets on out. On a DescribeDBInstances failure it falls back
+// to the last known-good scan of this region, if any, rather than dropping
+// the region's series entirely; StaleSeconds then tells operators how stale
+// that fallback is.
+func (d *WalkDiscoverer) discoverRegion(ctx context.Context, regionName string, filter Filter, out chan<- RDSTarget) {
+	logger := d.logger.With("region", regionName)
+	rdsClient := rds.NewFromConfig(regionSDKConfig(d.sdkConfig, regionName, filter.AssumeRoleARN(regionName)))
+
+	var targets []RDSTarget
+	var marker *string
+	for {
+		output, err := rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{Marker: marker})
+		if err != nil {
+			logger.Error("Couldn't list RDS instances", "error", err)
+			d.mu.Lock()
+			d.errCounts[regionName]++
+			d.mu.Unlock()
+			d.serveStale(regionName, out)
+			return
+		}
+
+		for _, instance := range output.DBInstances {
+			target := RDSTarget{
+				Region:                    regionName,
+				ARN:                       aws.ToString(instance.DBInstanceArn),
+				Identifier:                aws.ToString(instance.DBInstanceIdentifier),
+				AvailabilityZone:          aws.ToString(instance.AvailabilityZone),
+				SecondaryAvailabilityZone: aws.ToString(instance.SecondaryAvailabilityZone),
+				StorageType:               aws.ToString(instance.StorageType),
+				Class:                     aws.ToString(instance.DBInstanceClass),
+				Engine:                    aws.ToString(instance.Engine),
+				AllocatedStorage:          instance.AllocatedStorage,
+				MaxAllocatedStorage:       instance.MaxAllocatedStorage,
+				IOPS:                      instance.Iops,
+				StorageThroughput:         instance.StorageThroughput,
+				Tags:                      make(map[string]string, len(filter.Tags)),
+			}
+
+			tagsOutput, err := rdsClient.ListTagsForResource(ctx, &rds.ListTagsForResourceInput{ResourceName: instance.DBInstanceArn})
+			if err != nil {
+				logger.Error("Error listing tags for RDS instance", "arn", target.ARN, "error", err)
+			} else {
+				for _, tag := range tagsOutput.TagList {
+					if tag.Key != nil && tag.Value != nil && slices.Contains(filter.Tags, *tag.Key) {
+						target.Tags[*tag.Key] = *tag.Value
+					}
+				}
+			}
+
+			targets = append(targets, target)
+		}
+
+		if output.Marker == nil {
+			break
+		}
+		marker = output.Marker
+	}
+
+	d.mu.Lock()
+	d.cache[regionName] = regionSnapshot{targets: targets, time: time.Now()}
+	d.mu.Unlock()
+
+	for _, target := range targets {
+		out <- target
+	}
+}
+
+// serveStale re-emits the last known-good scan of regionName, if any, so a
+// single failing DescribeDBInstances call doesn't blank out that region's
+// series until the next successful scan.
+func (d *WalkDiscoverer) serveStale(regionName string, out chan<- RDSTarget) {
+	d.mu.RLock()
+	snapshot, ok := d.cache[regionName]
+	d.mu.RUnlock()
+	if !ok {
+		return
+	}
+	for _, target := range snapshot.targets {
+		out <- target
+	}
+}
+
+// StaleSeconds reports, for every region scanned so far, how long ago its
+// last successful scan completed.
+func (d *WalkDiscoverer) StaleSeconds() map[string]float64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	result := make(map[string]float64, len(d.cache))
+	for region, snapshot := range d.cache {
+		result[region] = time.Since(snapshot.time).Seconds()
+	}
+	return result
+}
+
+// RegionErrors returns the number of DescribeDBInstances failures per region
+// since the last call, then resets the counts.
+func (d *WalkDiscoverer) RegionErrors() map[string]int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	result := d.errCounts
+	d.errCounts = map[string]int{}
+	return result
+}
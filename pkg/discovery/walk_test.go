@@ -0,0 +1,41 @@
+package discovery
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func noopLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+func TestWalkDiscovererServeStaleAndStaleSeconds(t *testing.T) {
+	d := NewWalkDiscoverer(aws.Config{}, noopLogger())
+	d.cache["us-east-1"] = regionSnapshot{
+		targets: []RDSTarget{{Region: "us-east-1", Identifier: "db-1"}},
+		time:    time.Now().Add(-5 * time.Minute),
+	}
+
+	out := make(chan RDSTarget, 1)
+	d.serveStale("us-east-1", out)
+	close(out)
+	served := <-out
+	assert.Equal(t, "db-1", served.Identifier)
+
+	stale := d.StaleSeconds()
+	assert.InDelta(t, 300, stale["us-east-1"], 5)
+}
+
+func TestWalkDiscovererRegionErrorsDrainsAndResets(t *testing.T) {
+	d := NewWalkDiscoverer(aws.Config{}, noopLogger())
+	d.errCounts["us-east-1"] = 3
+
+	errs := d.RegionErrors()
+	assert.Equal(t, 3, errs["us-east-1"])
+	assert.Empty(t, d.RegionErrors())
+}
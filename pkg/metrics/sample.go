@@ -0,0 +1,13 @@
+// Package metrics holds the exporter's internal representation of a scraped
+// data point, independent of how it's eventually served: as a
+// prometheus.Metric on /metrics, a remote-write time series, or a persisted
+// MetricStore entry.
+package metrics
+
+// Sample is one RDS metric observation: a metric name, its label set, and
+// its value at collection time.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
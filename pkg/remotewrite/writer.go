@@ -0,0 +1,230 @@
+// Package remotewrite pushes the collector's cached samples to one or more
+// Prometheus remote-write v1 endpoints, so the exporter can run in "agent"
+// mode inside a VPC with no inbound scrape connectivity.
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/deeb00/rdscli_exporter/pkg/config"
+	"github.com/deeb00/rdscli_exporter/pkg/metrics"
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+var droppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "aws_rds_exporter_remote_write_dropped_total",
+	Help: "Total number of samples dropped instead of being remote-written, by endpoint and reason",
+}, []string{"endpoint", "reason"})
+
+func init() {
+	prometheus.MustRegister(droppedTotal)
+}
+
+// Writer batches samples enqueued from each cache refresh and ships them to
+// every configured endpoint. Enqueue is non-blocking: once the bounded queue
+// is full, further samples for that tick are dropped and counted so a slow
+// or down endpoint can't back-pressure the collector loop.
+type Writer struct {
+	endpoints         []config.RemoteWriteEndpoint
+	externalLabels    map[string]string
+	maxSamplesPerSend int
+	retryInitialDelay time.Duration
+	retryMaxDelay     time.Duration
+
+	queue  chan metrics.Sample
+	client *http.Client
+}
+
+// NewWriter builds a Writer from cfg. Call Run to start draining the queue.
+func NewWriter(cfg config.RemoteWriteConfig) *Writer {
+	return &Writer{
+		endpoints:         cfg.Endpoints,
+		externalLabels:    cfg.ExternalLabels,
+		maxSamplesPerSend: cfg.MaxSamplesPerSend,
+		retryInitialDelay: cfg.RetryInitialDelay,
+		retryMaxDelay:     cfg.RetryMaxDelay,
+		queue:             make(chan metrics.Sample, cfg.QueueSize),
+		client:            &http.Client{},
+	}
+}
+
+// Enqueue offers samples for the next flush. It never blocks: if the queue
+// is full, the remaining samples are dropped and counted per endpoint.
+func (w *Writer) Enqueue(samples []metrics.Sample) {
+	for _, s := range samples {
+		select {
+		case w.queue <- s:
+		default:
+			for _, ep := range w.endpoints {
+				droppedTotal.WithLabelValues(ep.URL, "queue_full").Inc()
+			}
+		}
+	}
+}
+
+// Run drains the queue until ctx is cancelled, flushing whenever the batch
+// reaches maxSamplesPerSend or a short deadline elapses, whichever is first.
+func (w *Writer) Run(ctx context.Context, logger *slog.Logger) {
+	if len(w.endpoints) == 0 {
+		return
+	}
+
+	const flushDeadline = time.Second
+	ticker := time.NewTicker(flushDeadline)
+	defer ticker.Stop()
+
+	batch := make([]metrics.Sample, 0, w.maxSamplesPerSend)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		w.send(ctx, batch, logger)
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			return
+		case <-ticker.C:
+			flush()
+		case s := <-w.queue:
+			batch = append(batch, s)
+			if len(batch) >= w.maxSamplesPerSend {
+				flush()
+			}
+		}
+	}
+}
+
+// send ships body to every configured endpoint concurrently, so a single
+// endpoint stuck retrying can't delay delivery to the others.
+func (w *Writer) send(ctx context.Context, batch []metrics.Sample, logger *slog.Logger) {
+	body, err := w.encode(batch)
+	if err != nil {
+		logger.Error("Error encoding remote-write batch", "error", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, ep := range w.endpoints {
+		wg.Add(1)
+		go func(ep config.RemoteWriteEndpoint) {
+			defer wg.Done()
+			if err := w.sendToEndpoint(ctx, ep, body, logger); err != nil {
+				logger.Error("Error sending remote-write batch", "endpoint", ep.URL, "error", err)
+			}
+		}(ep)
+	}
+	wg.Wait()
+}
+
+func (w *Writer) encode(batch []metrics.Sample) ([]byte, error) {
+	series := make([]prompb.TimeSeries, 0, len(batch))
+	now := time.Now().UnixMilli()
+	for _, s := range batch {
+		series = append(series, prompb.TimeSeries{
+			Labels: w.labelPairs(s),
+			Samples: []prompb.Sample{{
+				Value:     s.Value,
+				Timestamp: now,
+			}},
+		})
+	}
+
+	req := &prompb.WriteRequest{Timeseries: series}
+	data, err := req.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("marshaling write request: %w", err)
+	}
+	return snappy.Encode(nil, data), nil
+}
+
+func (w *Writer) labelPairs(s metrics.Sample) []prompb.Label {
+	labels := make([]prompb.Label, 0, len(s.Labels)+len(w.externalLabels)+1)
+	labels = append(labels, prompb.Label{Name: "__name__", Value: s.Name})
+	for name, value := range s.Labels {
+		labels = append(labels, prompb.Label{Name: name, Value: value})
+	}
+	for name, value := range w.externalLabels {
+		labels = append(labels, prompb.Label{Name: name, Value: value})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
+
+// maxSendAttempts bounds how many times sendToEndpoint retries a single
+// batch against one endpoint. Without a cap, an endpoint stuck returning 5xx
+// retries forever and, since endpoints are sent to concurrently but each
+// send() call waits for every endpoint to finish, would eventually wedge the
+// writer behind its own backoff once the bounded queue fills up.
+const maxSendAttempts = 5
+
+func (w *Writer) sendToEndpoint(ctx context.Context, ep config.RemoteWriteEndpoint, body []byte, logger *slog.Logger) error {
+	delay := w.retryInitialDelay
+
+	for attempt := 1; ; attempt++ {
+		status, err := w.post(ctx, ep, body)
+		if err == nil && status < 300 {
+			return nil
+		}
+		if err == nil && status >= 400 && status < 500 {
+			droppedTotal.WithLabelValues(ep.URL, "client_error").Inc()
+			return fmt.Errorf("remote write to %s rejected with status %d", ep.URL, status)
+		}
+		if attempt >= maxSendAttempts {
+			droppedTotal.WithLabelValues(ep.URL, "retries_exhausted").Inc()
+			return fmt.Errorf("remote write to %s failed after %d attempts (status=%d): %v", ep.URL, attempt, status, err)
+		}
+
+		logger.Warn("Remote write attempt failed, retrying", "endpoint", ep.URL, "status", status, "error", err, "delay", delay, "attempt", attempt)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > w.retryMaxDelay {
+			delay = w.retryMaxDelay
+		}
+	}
+}
+
+func (w *Writer) post(ctx context.Context, ep config.RemoteWriteEndpoint, body []byte) (int, error) {
+	timeout := ep.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if ep.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+ep.BearerToken)
+	} else if ep.BasicAuthUser != "" {
+		req.SetBasicAuth(ep.BasicAuthUser, ep.BasicAuthPass)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,91 @@
+package remotewrite
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deeb00/rdscli_exporter/pkg/config"
+	"github.com/deeb00/rdscli_exporter/pkg/metrics"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	w := NewWriter(config.RemoteWriteConfig{ExternalLabels: map[string]string{"cluster": "prod"}})
+
+	body, err := w.encode([]metrics.Sample{
+		{Name: "aws_rds_iops", Labels: map[string]string{"region": "us-east-1"}, Value: 42},
+	})
+	require.NoError(t, err)
+
+	decoded, err := snappy.Decode(nil, body)
+	require.NoError(t, err)
+
+	var req prompb.WriteRequest
+	require.NoError(t, req.Unmarshal(decoded))
+
+	require.Len(t, req.Timeseries, 1)
+	labels := map[string]string{}
+	for _, l := range req.Timeseries[0].Labels {
+		labels[l.Name] = l.Value
+	}
+	assert.Equal(t, "aws_rds_iops", labels["__name__"])
+	assert.Equal(t, "us-east-1", labels["region"])
+	assert.Equal(t, "prod", labels["cluster"])
+	require.Len(t, req.Timeseries[0].Samples, 1)
+	assert.Equal(t, float64(42), req.Timeseries[0].Samples[0].Value)
+}
+
+func TestSendToEndpointSucceedsWithoutRetry(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	w := NewWriter(config.RemoteWriteConfig{RetryInitialDelay: time.Millisecond, RetryMaxDelay: time.Millisecond})
+	err := w.sendToEndpoint(context.Background(), config.RemoteWriteEndpoint{URL: server.URL}, []byte("body"), discardLogger())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestSendToEndpointDoesNotRetryClientErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		rw.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	w := NewWriter(config.RemoteWriteConfig{RetryInitialDelay: time.Millisecond, RetryMaxDelay: time.Millisecond})
+	err := w.sendToEndpoint(context.Background(), config.RemoteWriteEndpoint{URL: server.URL}, []byte("body"), discardLogger())
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestSendToEndpointBoundsRetriesOnPersistent5xx(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	w := NewWriter(config.RemoteWriteConfig{RetryInitialDelay: time.Millisecond, RetryMaxDelay: time.Millisecond})
+	err := w.sendToEndpoint(context.Background(), config.RemoteWriteEndpoint{URL: server.URL}, []byte("body"), discardLogger())
+	require.Error(t, err)
+	assert.Equal(t, int32(maxSendAttempts), atomic.LoadInt32(&requests))
+}
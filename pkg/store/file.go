@@ -0,0 +1,54 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/deeb00/rdscli_exporter/pkg/metrics"
+	"github.com/deeb00/rdscli_exporter/pkg/utils"
+)
+
+// fileRecord is the gob-encoded payload written to disk by FileStore.
+type fileRecord struct {
+	Samples []metrics.Sample
+	Time    time.Time
+}
+
+// FileStore persists samples to a single gob-encoded file, written after
+// every successful refresh via utils.SaveToFile.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore returns a MetricStore backed by the file at path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) Get(_ context.Context) ([]metrics.Sample, time.Time, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("reading metric store file %s: %w", s.path, err)
+	}
+
+	var record fileRecord
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&record); err != nil {
+		return nil, time.Time{}, fmt.Errorf("decoding metric store file %s: %w", s.path, err)
+	}
+	return record.Samples, record.Time, nil
+}
+
+func (s *FileStore) Put(_ context.Context, samples []metrics.Sample, ts time.Time) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(fileRecord{Samples: samples, Time: ts}); err != nil {
+		return fmt.Errorf("encoding metric store file %s: %w", s.path, err)
+	}
+	return utils.SaveToFile(s.path, buf.Bytes())
+}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/deeb00/rdscli_exporter/pkg/metrics"
+)
+
+// MemoryStore is the pre-persistence behavior: nothing survives a restart.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	samples []metrics.Sample
+	ts      time.Time
+}
+
+// NewMemoryStore returns an empty in-memory MetricStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Get(_ context.Context) ([]metrics.Sample, time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.samples, s.ts, nil
+}
+
+func (s *MemoryStore) Put(_ context.Context, samples []metrics.Sample, ts time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.samples = samples
+	s.ts = ts
+	return nil
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"github.com/deeb00/rdscli_exporter/pkg/metrics"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore persists samples to Redis, one key per region under namespace
+// (typically the AWS account ID) so multiple accounts/exporters can share a
+// single Redis instance without clobbering each other.
+type RedisStore struct {
+	client    *redis.Client
+	namespace string
+	ttl       time.Duration
+}
+
+// NewRedisStore returns a MetricStore backed by client, keyed under
+// namespace. ttl expires stale region keys so a decommissioned region
+// doesn't linger forever; 0 disables expiry.
+func NewRedisStore(client *redis.Client, namespace string, ttl time.Duration) *RedisStore {
+	return &RedisStore{client: client, namespace: namespace, ttl: ttl}
+}
+
+type redisRegionRecord struct {
+	Samples []metrics.Sample
+	Time    time.Time
+}
+
+func (s *RedisStore) regionKey(region string) string {
+	return fmt.Sprintf("rds_exporter:%s:%s", s.namespace, region)
+}
+
+func (s *RedisStore) Get(ctx context.Context) ([]metrics.Sample, time.Time, error) {
+	keys, err := s.client.Keys(ctx, fmt.Sprintf("rds_exporter:%s:*", s.namespace)).Result()
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("listing redis keys under %s: %w", s.namespace, err)
+	}
+
+	var samples []metrics.Sample
+	var latest time.Time
+	for _, key := range keys {
+		data, err := s.client.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("reading redis key %s: %w", key, err)
+		}
+
+		var record redisRegionRecord
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&record); err != nil {
+			return nil, time.Time{}, fmt.Errorf("decoding redis key %s: %w", key, err)
+		}
+		samples = append(samples, record.Samples...)
+		if record.Time.After(latest) {
+			latest = record.Time
+		}
+	}
+	return samples, latest, nil
+}
+
+func (s *RedisStore) Put(ctx context.Context, samples []metrics.Sample, ts time.Time) error {
+	byRegion := make(map[string][]metrics.Sample)
+	for _, sample := range samples {
+		byRegion[sample.Labels["region"]] = append(byRegion[sample.Labels["region"]], sample)
+	}
+
+	for region, regionSamples := range byRegion {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(redisRegionRecord{Samples: regionSamples, Time: ts}); err != nil {
+			return fmt.Errorf("encoding redis record for region %s: %w", region, err)
+		}
+		if err := s.client.Set(ctx, s.regionKey(region), buf.Bytes(), s.ttl).Err(); err != nil {
+			return fmt.Errorf("writing redis key for region %s: %w", region, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+// Package store persists the collector's samples across restarts so the
+// exporter has something useful to serve immediately on startup, instead of
+// a metrics gap until the first cache refresh completes.
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/deeb00/rdscli_exporter/pkg/metrics"
+)
+
+// MetricStore is a secondary cache for the collector's samples. Put is
+// called after every successful refresh; Get is called once at startup to
+// warm the in-memory view.
+type MetricStore interface {
+	Get(ctx context.Context) ([]metrics.Sample, time.Time, error)
+	Put(ctx context.Context, samples []metrics.Sample, ts time.Time) error
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/deeb00/rdscli_exporter/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStorePutGet(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := context.Background()
+
+	samples, ts, err := s.Get(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+	assert.True(t, ts.IsZero())
+
+	want := []metrics.Sample{
+		{Name: "aws_rds_iops", Labels: map[string]string{"region": "us-east-1"}, Value: 42},
+	}
+	wantTime := time.Now().Truncate(time.Second)
+	require.NoError(t, s.Put(ctx, want, wantTime))
+
+	samples, ts, err = s.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, want, samples)
+	assert.True(t, ts.Equal(wantTime))
+}
+
+func TestFileStorePutGet(t *testing.T) {
+	s := NewFileStore(filepath.Join(t.TempDir(), "cache.gob"))
+	ctx := context.Background()
+
+	samples, ts, err := s.Get(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+	assert.True(t, ts.IsZero())
+
+	want := []metrics.Sample{
+		{Name: "aws_rds_allocated_storage", Labels: map[string]string{"region": "eu-west-1"}, Value: 100},
+	}
+	wantTime := time.Now().Truncate(time.Second)
+	require.NoError(t, s.Put(ctx, want, wantTime))
+
+	samples, ts, err = s.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, want, samples)
+	assert.True(t, ts.Equal(wantTime))
+}
+
+func TestFileStoreGetMissingFile(t *testing.T) {
+	s := NewFileStore(filepath.Join(t.TempDir(), "does-not-exist.gob"))
+	samples, ts, err := s.Get(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+	assert.True(t, ts.IsZero())
+}
@@ -1,28 +1,121 @@
 package utils
 
 import (
-	"github.com/sirupsen/logrus"
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
 )
 
-func InitLogging(appName string, logLevel string) (*logrus.Entry, error) {
-	log := logrus.New()
-	log.SetFormatter(&logrus.JSONFormatter{
-		FieldMap: logrus.FieldMap{
-			logrus.FieldKeyTime:  "@timestamp",
-			logrus.FieldKeyLevel: "level",
-			logrus.FieldKeyMsg:   "message",
-		},
-	})
-
-	level, err := logrus.ParseLevel(logLevel)
+// InitLogging builds the process-wide structured logger: JSON output with
+// ELK-friendly field names (@timestamp, level, message) and the app name
+// attached to every record. Identical records repeated within dedupeWindow
+// are dropped; pass 0 to disable deduping.
+func InitLogging(appName string, logLevel string, dedupeWindow time.Duration) (*slog.Logger, error) {
+	level, err := LookupSlogLevel(logLevel)
 	if err != nil {
-		log.WithError(err).Error("Error parsing log level")
 		return nil, err
 	}
 
-	log.SetLevel(level)
+	var handler slog.Handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: elkAttrs,
+	})
+	if dedupeWindow > 0 {
+		handler = NewDedupingHandler(handler, dedupeWindow)
+	}
+
+	return slog.New(handler).With("app", appName), nil
+}
+
+func elkAttrs(_ []string, a slog.Attr) slog.Attr {
+	switch a.Key {
+	case slog.TimeKey:
+		a.Key = "@timestamp"
+	case slog.LevelKey:
+		a.Key = "level"
+	case slog.MessageKey:
+		a.Key = "message"
+	}
+	return a
+}
+
+// LookupSlogLevel parses a textual log level ("debug", "info", "warn",
+// "error"), so the level can come from -log.level or LOG_LEVEL.
+func LookupSlogLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return 0, err
+	}
+	return l, nil
+}
+
+// DedupingHandler wraps a slog.Handler and drops records that repeat a
+// level+message+attributes combination already seen within window, tracking
+// every distinct combination rather than just the most recent one, so
+// unrelated log lines interleaved by other goroutines (other regions, the
+// config watcher, remote-write) don't reset the dedup. Only a record's own
+// attributes form the key. Each handler returned by WithAttrs or WithGroup
+// starts with its own empty set of seen records, so a record logged through
+// a newly derived logger (such as one built with logger.With on every call)
+// is never suppressed by earlier ones.
+type DedupingHandler struct {
+	next   slog.Handler
+	window time.Duration
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+}
+
+// NewDedupingHandler wraps next, suppressing repeated identical records
+// within window.
+func NewDedupingHandler(next slog.Handler, window time.Duration) *DedupingHandler {
+	return &DedupingHandler{next: next, window: window, lastSeen: map[string]time.Time{}}
+}
+
+func (h *DedupingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *DedupingHandler) Handle(ctx context.Context, record slog.Record) error {
+	key := recordKey(record)
+	now := record.Time
+
+	h.mu.Lock()
+	last, seen := h.lastSeen[key]
+	suppress := seen && now.Sub(last) < h.window
+	if !suppress {
+		h.lastSeen[key] = now
+		// Opportunistic cleanup so a long-running process with many
+		// distinct messages doesn't keep every key it's ever seen forever.
+		for k, t := range h.lastSeen {
+			if now.Sub(t) >= h.window {
+				delete(h.lastSeen, k)
+			}
+		}
+	}
+	h.mu.Unlock()
+
+	if suppress {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *DedupingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &DedupingHandler{next: h.next.WithAttrs(attrs), window: h.window, lastSeen: map[string]time.Time{}}
+}
 
-	return log.WithFields(logrus.Fields{
-		"app": appName,
-	}), nil
+func (h *DedupingHandler) WithGroup(name string) slog.Handler {
+	return &DedupingHandler{next: h.next.WithGroup(name), window: h.window, lastSeen: map[string]time.Time{}}
+}
+
+func recordKey(record slog.Record) string {
+	key := record.Level.String() + "|" + record.Message
+	record.Attrs(func(a slog.Attr) bool {
+		key += "|" + a.Key + "=" + a.Value.String()
+		return true
+	})
+	return key
 }
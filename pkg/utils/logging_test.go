@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler collects every record handed to it, so tests can assert
+// on exactly what made it through DedupingHandler.
+type recordingHandler struct {
+	messages []string
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	h.messages = append(h.messages, record.Message)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler      { return h }
+
+func newRecord(msg string, t time.Time) slog.Record {
+	return slog.NewRecord(t, slog.LevelError, msg, 0)
+}
+
+func TestDedupingHandlerTracksEachKeyIndependently(t *testing.T) {
+	next := &recordingHandler{}
+	h := NewDedupingHandler(next, time.Minute)
+	base := time.Now()
+
+	// The repeated message (us-east-1 failing every tick) is interleaved
+	// with an unrelated one from another goroutine, which used to reset a
+	// single global lastKey and defeat the dedup entirely.
+	require.NoError(t, h.Handle(context.Background(), newRecord("region us-east-1 failed", base)))
+	require.NoError(t, h.Handle(context.Background(), newRecord("region eu-west-1 failed", base)))
+	require.NoError(t, h.Handle(context.Background(), newRecord("region us-east-1 failed", base.Add(time.Second))))
+	require.NoError(t, h.Handle(context.Background(), newRecord("region eu-west-1 failed", base.Add(time.Second))))
+
+	assert.Equal(t, []string{"region us-east-1 failed", "region eu-west-1 failed"}, next.messages)
+}
+
+func TestDedupingHandlerAllowsRepeatAfterWindow(t *testing.T) {
+	next := &recordingHandler{}
+	h := NewDedupingHandler(next, time.Second)
+	base := time.Now()
+
+	require.NoError(t, h.Handle(context.Background(), newRecord("region us-east-1 failed", base)))
+	require.NoError(t, h.Handle(context.Background(), newRecord("region us-east-1 failed", base.Add(2*time.Second))))
+
+	assert.Equal(t, []string{"region us-east-1 failed", "region us-east-1 failed"}, next.messages)
+}